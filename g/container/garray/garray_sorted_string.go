@@ -7,6 +7,11 @@
 package garray
 
 import (
+    "bytes"
+    "encoding/gob"
+    "encoding/json"
+    "fmt"
+    "gitee.com/johng/gf/g/container/garray/iterator"
     "gitee.com/johng/gf/g/container/gtype"
     "gitee.com/johng/gf/g/internal/rwmutex"
     "gitee.com/johng/gf/g/util/gconv"
@@ -18,10 +23,16 @@ import (
 
 // 默认按照从小到大进行排序
 type SortedStringArray struct {
-    mu          *rwmutex.RWMutex        // 互斥锁
-    array       []string                // 底层数组
-    unique      *gtype.Bool             // 是否要求不能重复
-    compareFunc func(v1, v2 string) int // 比较函数，返回值 -1: v1 < v2；0: v1 == v2；1: v1 > v2
+    mu           *rwmutex.RWMutex    // 互斥锁
+    array        []string            // 底层数组
+    unique       *gtype.Bool         // 是否要求不能重复
+    compareFunc  iterator.Comparator // 比较函数，返回值 -1: v1 < v2；0: v1 == v2；1: v1 > v2
+    indexEnabled *gtype.Bool         // 是否开启子串索引(后缀数组)
+    indexDirty   *gtype.Bool         // 索引是否需要重建
+    corpus       string              // 索引重建时使用的拼接语料(各元素以哨兵字节分隔)
+    offsets      []int               // 各元素在corpus中的起始偏移量，与array一一对应
+    suffixArray  []int               // 后缀数组，suffixArray[i]为排名第i的后缀在corpus中的起始位置
+    suffixRank   []int               // 逆数组，suffixRank[i]为corpus中起始位置为i的后缀在suffixArray中的排名
 }
 
 // Create an empty sorted array.
@@ -35,9 +46,11 @@ func NewSortedStringArray(unsafe...bool) *SortedStringArray {
 
 func NewSortedStringArraySize(cap int, unsafe...bool) *SortedStringArray {
     return &SortedStringArray {
-        mu          : rwmutex.New(unsafe...),
-        array       : make([]string, 0, cap),
-        unique      : gtype.NewBool(),
+        mu           : rwmutex.New(unsafe...),
+        array        : make([]string, 0, cap),
+        unique       : gtype.NewBool(),
+        indexEnabled : gtype.NewBool(),
+        indexDirty   : gtype.NewBool(),
         compareFunc : func(v1, v2 string) int {
             return strings.Compare(v1, v2)
         },
@@ -47,7 +60,7 @@ func NewSortedStringArraySize(cap int, unsafe...bool) *SortedStringArray {
 func NewSortedStringArrayFrom(array []string, unsafe...bool) *SortedStringArray {
     a := NewSortedStringArraySize(0, unsafe...)
     a.array = array
-    sort.Strings(a.array)
+    a.sortArray()
     return a
 }
 
@@ -56,7 +69,8 @@ func (a *SortedStringArray) SetArray(array []string) *SortedStringArray {
     a.mu.Lock()
     defer a.mu.Unlock()
     a.array = array
-    sort.Strings(a.array)
+    a.sortArray()
+    a.indexDirty.Set(true)
     return a
 }
 
@@ -64,10 +78,21 @@ func (a *SortedStringArray) SetArray(array []string) *SortedStringArray {
 func (a *SortedStringArray) Sort() *SortedStringArray {
     a.mu.Lock()
     defer a.mu.Unlock()
-    sort.Strings(a.array)
+    a.sortArray()
     return a
 }
 
+// sortArray按照当前的compareFunc对底层数组重新排序，调用方需要持有写锁。
+func (a *SortedStringArray) sortArray() {
+    if a.compareFunc != nil {
+        sort.Slice(a.array, func(i, j int) bool {
+            return a.compareFunc(a.array[i], a.array[j]) < 0
+        })
+    } else {
+        sort.Strings(a.array)
+    }
+}
+
 // 添加加数据项
 func (a *SortedStringArray) Add(values...string) *SortedStringArray {
     if len(values) == 0 {
@@ -92,6 +117,7 @@ func (a *SortedStringArray) Add(values...string) *SortedStringArray {
         a.array = append(a.array[0 : index], value)
         a.array = append(a.array, rear...)
     }
+    a.indexDirty.Set(true)
     return a
 }
 
@@ -111,15 +137,18 @@ func (a *SortedStringArray) Remove(index int) string {
     if index == 0 {
         value  := a.array[0]
         a.array = a.array[1 : ]
+        a.indexDirty.Set(true)
         return value
     } else if index == len(a.array) - 1 {
         value  := a.array[index]
         a.array = a.array[: index]
+        a.indexDirty.Set(true)
         return value
     }
     // 如果非边界删除，会涉及到数组创建，那么删除的效率差一些
     value  := a.array[index]
     a.array = append(a.array[ : index], a.array[index + 1 : ]...)
+    a.indexDirty.Set(true)
     return value
 }
 
@@ -129,6 +158,7 @@ func (a *SortedStringArray) PopLeft() string {
     defer a.mu.Unlock()
     value  := a.array[0]
     a.array = a.array[1 : ]
+    a.indexDirty.Set(true)
     return value
 }
 
@@ -139,6 +169,7 @@ func (a *SortedStringArray) PopRight() string {
     index  := len(a.array) - 1
     value  := a.array[index]
     a.array = a.array[: index]
+    a.indexDirty.Set(true)
     return value
 }
 
@@ -233,6 +264,7 @@ func (a *SortedStringArray) Unique() *SortedStringArray {
         }
         if a.compareFunc(a.array[i], a.array[i + 1]) == 0 {
             a.array = append(a.array[ : i + 1], a.array[i + 1 + 1 : ]...)
+            a.indexDirty.Set(true)
         } else {
             i++
         }
@@ -258,6 +290,7 @@ func (a *SortedStringArray) Clear() *SortedStringArray {
     if len(a.array) > 0 {
         a.array = make([]string, 0)
     }
+    a.indexDirty.Set(true)
     a.mu.Unlock()
     return a
 }
@@ -287,7 +320,8 @@ func (a *SortedStringArray) Merge(array *SortedStringArray) *SortedStringArray {
         defer array.mu.RUnlock()
     }
     a.array = append(a.array, array.array...)
-    sort.Strings(a.array)
+    a.sortArray()
+    a.indexDirty.Set(true)
     return a
 }
 
@@ -362,4 +396,592 @@ func (a *SortedStringArray) Join(glue string) string {
     a.mu.RLock()
     defer a.mu.RUnlock()
     return strings.Join(a.array, glue)
+}
+
+// indexSentinel分隔corpus中各元素，要求不出现在被索引的字符串内容中.
+const indexSentinel = byte(0)
+
+// EnableIndex开启(或关闭)子串索引。开启之后，SearchSubstring/SearchPrefix
+// 使用后缀数组进行O(m logN)的查找；未开启时二者不做任何查找，直接返回nil。
+// 索引的构建是惰性的：开启之后并不会立即构建，而是在下一次查询时按需构建，
+// 并在Add/Remove/Clear/Merge/SetArray之后自动标记为失效，下次查询时重建。
+func (a *SortedStringArray) EnableIndex(enabled bool) *SortedStringArray {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.indexEnabled.Set(enabled)
+    if enabled {
+        a.indexDirty.Set(true)
+    } else {
+        a.corpus      = ""
+        a.offsets     = nil
+        a.suffixArray = nil
+        a.suffixRank  = nil
+    }
+    return a
+}
+
+// rebuildIndexIfDirty在索引被标记为脏时重建它。调用方必须已经持有写锁，
+// 且必须在重建完成后，于同一次加锁期间内完成后续的索引读取——如果重建
+// 和读取分别加锁，两次加锁之间可能发生Remove/PopLeft/PopRight/Clear等
+// 并发修改，导致读取到的仍然是重建前(针对旧array)的suffixArray/offsets/
+// corpus，从而返回在当前array中已经不存在的元素索引。
+func (a *SortedStringArray) rebuildIndexIfDirty() {
+    if a.indexDirty.Val() {
+        a.rebuildIndex()
+        a.indexDirty.Set(false)
+    }
+}
+
+// rebuildIndex将当前数组的所有元素以indexSentinel分隔拼接成corpus，
+// 记录每个元素在corpus中的起始偏移量，并对corpus的所有后缀构建后缀数组。
+// 调用方需要持有写锁。
+func (a *SortedStringArray) rebuildIndex() {
+    offsets := make([]int, len(a.array))
+    builder := make([]byte, 0)
+    for i, v := range a.array {
+        offsets[i] = len(builder)
+        builder = append(builder, v...)
+        builder = append(builder, indexSentinel)
+    }
+    a.offsets = offsets
+    a.corpus  = string(builder)
+    a.suffixArray, a.suffixRank = buildSuffixArray(builder)
+}
+
+// buildSuffixArray使用Larsson & Sadakane提出的qsufsort算法对corpus的所有
+// 后缀进行排序，返回后缀数组sa(sa[i]为排名第i的后缀的起始位置)及其逆数组
+// rank(rank[i]为起始位置为i的后缀的排名/分组编号)。
+//
+// 算法先按首字符做一次桶排序得到初始分组，随后不断将比较长度h加倍
+// (h = 1, 2, 4, 8, ...)：同一分组内的后缀按"自身分组编号"及"往后h个字符
+// 处的后缀分组编号"重新排序和拆分。已经拆分为单元素的分组(即已经完全
+// 确定相对顺序)通过负的分组长度标记为finished，后续轮次直接跳过，
+// 从而使每一轮只需处理O(N/h)个尚未确定的分组。
+func buildSuffixArray(corpus []byte) (sa []int, rank []int) {
+    n := len(corpus)
+    sa   = make([]int, n)
+    rank = make([]int, n)
+    if n == 0 {
+        return sa, rank
+    }
+    for i := range sa {
+        sa[i] = i
+    }
+    sort.Slice(sa, func(i, j int) bool {
+        return corpus[sa[i]] < corpus[sa[j]]
+    })
+    // groupLen[i]记录以sa中索引i为首的分组长度；负值表示该分组已经是
+    // 单元素(finished)，扫描时可以直接跳过。
+    groupLen := make([]int, n)
+    group := 0
+    for i := 0; i < n; i++ {
+        if i == 0 || corpus[sa[i]] != corpus[sa[i-1]] {
+            group = i
+        }
+        rank[sa[i]] = group
+    }
+    markSuffixGroup := func(start, end int) {
+        for k := start; k < end; k++ {
+            rank[sa[k]] = start
+        }
+        if end-start == 1 {
+            groupLen[start] = -1
+        } else {
+            groupLen[start] = end - start
+        }
+    }
+    for i := 0; i < n; {
+        j := i + 1
+        for j < n && rank[sa[j]] == rank[sa[i]] {
+            j++
+        }
+        markSuffixGroup(i, j)
+        i = j
+    }
+    for h := 1; ; h *= 2 {
+        finished := true
+        // oldRank冻结了本轮h开始时的分组编号。本轮内，某个分组的拆分会
+        // 立即把新的编号写回rank，供下一轮使用；但如果同一轮里后面处理
+        // 的分组又通过p+h读到这些刚刚写入的新编号，就会用"半新半旧"的
+        // 状态参与比较，导致排序错误。因此本轮的所有keyAt查找都必须读
+        // 取这份不变的快照，rank只在每个分组拆分完成后才被更新。
+        oldRank := append([]int(nil), rank...)
+        keyAt := func(p int) int {
+            if p+h < n {
+                return oldRank[p+h]
+            }
+            return -1
+        }
+        for i := 0; i < n; {
+            if groupLen[i] < 0 {
+                i++
+                continue
+            }
+            length := groupLen[i]
+            finished = false
+            group := sa[i : i+length]
+            sort.Slice(group, func(x, y int) bool {
+                return keyAt(group[x]) < keyAt(group[y])
+            })
+            subStart := i
+            for k := i + 1; k < i+length; k++ {
+                if keyAt(sa[k]) != keyAt(sa[k-1]) {
+                    markSuffixGroup(subStart, k)
+                    subStart = k
+                }
+            }
+            markSuffixGroup(subStart, i+length)
+            i += length
+        }
+        if finished {
+            break
+        }
+    }
+    return sa, rank
+}
+
+// suffixRange在后缀数组中二分查找所有以substr为前缀的后缀所在的
+// [lo, hi)区间。调用方需要持有读锁(或写锁)。
+func (a *SortedStringArray) suffixRange(substr string) (lo, hi int) {
+    n := len(a.suffixArray)
+    lo = sort.Search(n, func(i int) bool {
+        return compareSuffixPrefix(a.corpus, a.suffixArray[i], substr) >= 0
+    })
+    hi = sort.Search(n, func(i int) bool {
+        return compareSuffixPrefix(a.corpus, a.suffixArray[i], substr) > 0
+    })
+    return lo, hi
+}
+
+// compareSuffixPrefix比较corpus中起始于offset的后缀与substr的大小关系，
+// 只比较min(len(suffix), len(substr))个字符：小于0表示后缀前缀小于substr，
+// 大于0表示大于，等于0表示后缀以substr为前缀。
+func compareSuffixPrefix(corpus string, offset int, substr string) int {
+    suffix := corpus[offset:]
+    m := len(substr)
+    if len(suffix) < m {
+        m = len(suffix)
+    }
+    if cmp := strings.Compare(suffix[:m], substr); cmp != 0 {
+        return cmp
+    }
+    if len(suffix) < len(substr) {
+        return -1
+    }
+    return 0
+}
+
+// elementIndexAt将corpus中的一个偏移量映射回它所属元素在array中的索引。
+func (a *SortedStringArray) elementIndexAt(offset int) int {
+    i := sort.Search(len(a.offsets), func(i int) bool {
+        return a.offsets[i] > offset
+    })
+    return i - 1
+}
+
+// SearchSubstring在数组的所有元素中查找包含substr的元素，返回去重后
+// 升序排列的元素索引。必须先调用EnableIndex(true)开启索引，否则总是
+// 返回nil。首次查询时会按需重建索引(惰性重建)。
+func (a *SortedStringArray) SearchSubstring(substr string) []int {
+    if substr == "" || !a.indexEnabled.Val() {
+        return nil
+    }
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.rebuildIndexIfDirty()
+    lo, hi := a.suffixRange(substr)
+    seen   := make(map[int]struct{})
+    result := make([]int, 0, hi-lo)
+    for i := lo; i < hi; i++ {
+        idx := a.elementIndexAt(a.suffixArray[i])
+        if _, ok := seen[idx]; ok {
+            continue
+        }
+        seen[idx] = struct{}{}
+        result = append(result, idx)
+    }
+    sort.Ints(result)
+    return result
+}
+
+// SearchPrefix在数组的所有元素中查找以prefix为前缀的元素，返回去重后
+// 升序排列的元素索引。必须先调用EnableIndex(true)开启索引，否则总是
+// 返回nil。首次查询时会按需重建索引(惰性重建)。
+func (a *SortedStringArray) SearchPrefix(prefix string) []int {
+    if !a.indexEnabled.Val() {
+        return nil
+    }
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.rebuildIndexIfDirty()
+    lo, hi := a.suffixRange(prefix)
+    result := make([]int, 0, hi-lo)
+    for i := lo; i < hi; i++ {
+        offset := a.suffixArray[i]
+        // 只有后缀恰好起始于某个元素的开头，才说明该元素以prefix为前缀。
+        if idx := a.elementIndexAt(offset); a.offsets[idx] == offset {
+            result = append(result, idx)
+        }
+    }
+    sort.Ints(result)
+    return result
+}
+
+// sortedStringArrayIterator是iterator.BidirectionalIterator在SortedStringArray
+// 上的实现。step为+1表示正向迭代(Begin/End)，为-1表示反向迭代(RBegin/REnd)。
+type sortedStringArrayIterator struct {
+    arr  *SortedStringArray
+    pos  int
+    step int
+}
+
+func (it *sortedStringArrayIterator) IsValid() bool {
+    it.arr.mu.RLock()
+    defer it.arr.mu.RUnlock()
+    return it.pos >= 0 && it.pos < len(it.arr.array)
+}
+
+func (it *sortedStringArrayIterator) Next() bool {
+    it.pos += it.step
+    return it.IsValid()
+}
+
+func (it *sortedStringArrayIterator) Prev() bool {
+    it.pos -= it.step
+    return it.IsValid()
+}
+
+func (it *sortedStringArrayIterator) Value() string {
+    it.arr.mu.RLock()
+    defer it.arr.mu.RUnlock()
+    return it.arr.array[it.pos]
+}
+
+func (it *sortedStringArrayIterator) SetValue(value string) {
+    it.arr.mu.Lock()
+    defer it.arr.mu.Unlock()
+    it.arr.array[it.pos] = value
+    it.arr.indexDirty.Set(true)
+}
+
+// Begin返回一个指向首个元素的正向迭代器。
+func (a *SortedStringArray) Begin() iterator.BidirectionalIterator {
+    return &sortedStringArrayIterator{arr: a, pos: 0, step: 1}
+}
+
+// End返回一个指向"末尾之后"位置的正向迭代器，该位置始终无效(IsValid为false)，
+// 仅用作遍历终点的哨兵。
+func (a *SortedStringArray) End() iterator.BidirectionalIterator {
+    a.mu.RLock()
+    n := len(a.array)
+    a.mu.RUnlock()
+    return &sortedStringArrayIterator{arr: a, pos: n, step: 1}
+}
+
+// RBegin返回一个指向最后一个元素的反向迭代器，调用Next()将使其向前一个
+// 元素(即索引减小的方向)移动。
+func (a *SortedStringArray) RBegin() iterator.BidirectionalIterator {
+    a.mu.RLock()
+    n := len(a.array)
+    a.mu.RUnlock()
+    return &sortedStringArrayIterator{arr: a, pos: n - 1, step: -1}
+}
+
+// REnd返回一个指向"首个元素之前"位置的反向迭代器，该位置始终无效，
+// 仅用作反向遍历终点的哨兵。
+func (a *SortedStringArray) REnd() iterator.BidirectionalIterator {
+    return &sortedStringArrayIterator{arr: a, pos: -1, step: -1}
+}
+
+// SetComparator替换数组当前使用的比较函数，并立即按照新的比较规则重新
+// 排序，从而支持大小写不敏感、自然排序、本地化排序等自定义比较逻辑，
+// 调用方无需关心内部compareFunc字段。
+func (a *SortedStringArray) SetComparator(cmp iterator.Comparator) *SortedStringArray {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.compareFunc = cmp
+    a.sortArray()
+    a.indexDirty.Set(true)
+    return a
+}
+
+// RemoveIf删除所有满足pred的元素，返回被删除的元素个数。内部通过
+// iterator.RemoveIf基于Begin()返回的BidirectionalIterator完成紧凑移动，
+// 与LowerBound/UpperBound/ForEach/CountIf复用同一套迭代器抽象，而不是
+// 自行重新扫描a.array。
+func (a *SortedStringArray) RemoveIf(pred func(value string) bool) int {
+    removed := iterator.RemoveIf(a.Begin(), pred)
+    if removed > 0 {
+        a.mu.Lock()
+        if removed <= len(a.array) {
+            a.array = a.array[:len(a.array)-removed]
+        }
+        a.indexDirty.Set(true)
+        a.mu.Unlock()
+    }
+    return removed
+}
+
+// IsEmpty判断数组是否为空.
+func (a *SortedStringArray) IsEmpty() bool {
+    return a.Len() == 0
+}
+
+// RemoveValue删除数组中的指定数据项，存在则删除并返回true，否则返回false.
+func (a *SortedStringArray) RemoveValue(value string) bool {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    index, cmp := a.binSearch(value, false)
+    if index < 0 || cmp != 0 {
+        return false
+    }
+    if index == 0 {
+        a.array = a.array[1:]
+    } else if index == len(a.array)-1 {
+        a.array = a.array[:index]
+    } else {
+        a.array = append(a.array[:index], a.array[index+1:]...)
+    }
+    a.indexDirty.Set(true)
+    return true
+}
+
+// CountValues统计数组中各个数据项分别出现的次数，返回值为 value => count 的map.
+func (a *SortedStringArray) CountValues() map[string]int {
+    a.mu.RLock()
+    defer a.mu.RUnlock()
+    m := make(map[string]int)
+    for _, v := range a.array {
+        m[v]++
+    }
+    return m
+}
+
+// Fill从startIndex开始，向数组中添加num个值为value的数据项，
+// 若startIndex超出当前数组范围则返回错误。由于数组本身是有序的，
+// 新添加的数据项最终会按照比较规则排列到正确的位置，而不是
+// 字面意义上插入到startIndex处，这里的startIndex仅用于边界校验，
+// 以保持与PHP array_fill风格API的参数形式一致。
+//
+// 注意：添加操作内部调用Add，因此当SetUnique(true)且value已经存在于
+// 数组中时，这num个数据项会被Add按照去重规则静默跳过，Fill仍然返回
+// nil(因为这不是一个“错误”，而是该数组本身的唯一性约束生效)——如果
+// 调用方需要知道实际新增了多少项，应自行在调用前后比较Len()。
+func (a *SortedStringArray) Fill(startIndex int, num int, value string) error {
+    a.mu.RLock()
+    length := len(a.array)
+    a.mu.RUnlock()
+    if startIndex < 0 || startIndex > length {
+        return fmt.Errorf("index %d out of bounds %d", startIndex, length)
+    }
+    if num <= 0 {
+        return nil
+    }
+    values := make([]string, num)
+    for i := 0; i < num; i++ {
+        values[i] = value
+    }
+    a.Add(values...)
+    return nil
+}
+
+// Pad将数组填充value直至长度达到size的绝对值。若当前长度已经不小于该
+// 绝对值则不做任何操作。size为负数时按照PHP array_pad的语义表示从数组
+// 头部填充，但由于本数组始终保持有序，填充的数据项最终都会被重新排序
+// 到由比较规则决定的位置，因此这里不区分头部/尾部，一律通过Add完成。
+//
+// 注意：与Fill一样，填充操作内部调用Add，因此当SetUnique(true)且value
+// 已经存在于数组中时，填充不会让数组达到size指定的长度——Add会按照
+// 去重规则静默跳过这些重复的value，Pad同样不会报告实际新增的数量。
+func (a *SortedStringArray) Pad(size int, value string) *SortedStringArray {
+    a.mu.RLock()
+    length := len(a.array)
+    a.mu.RUnlock()
+    targetLen := size
+    if targetLen < 0 {
+        targetLen = -targetLen
+    }
+    if targetLen <= length {
+        return a
+    }
+    values := make([]string, targetLen-length)
+    for i := range values {
+        values[i] = value
+    }
+    a.Add(values...)
+    return a
+}
+
+// Walk对数组中的每一个数据项调用f，并用其返回值替换原数据项，
+// 最后重新排序(因为f可能会改变数据项原本的相对顺序)；若unique为true，
+// 还会重新去除f可能引入的重复数据项。
+func (a *SortedStringArray) Walk(f func(value string) string) *SortedStringArray {
+    a.mu.Lock()
+    for i, v := range a.array {
+        a.array[i] = f(v)
+    }
+    a.sortArray()
+    a.indexDirty.Set(true)
+    a.mu.Unlock()
+    // f可能会把原本不同的数据项映射成相同的值，因此和UnmarshalJSON/
+    // GobDecode一样，在unique为true时需要重新去重，以维持该数组的
+    // 唯一性约束。
+    if a.unique.Val() {
+        a.Unique()
+    }
+    return a
+}
+
+// Reverse返回当前数组按逆序排列的一个拷贝(类似PHP array_reverse)。
+// 由于本数组始终保持有序，返回的是一个普通的[]string而不是
+// *SortedStringArray，避免破坏有序数组的不变性。
+func (a *SortedStringArray) Reverse() []string {
+    a.mu.RLock()
+    defer a.mu.RUnlock()
+    n      := len(a.array)
+    result := make([]string, n)
+    for i, v := range a.array {
+        result[n-1-i] = v
+    }
+    return result
+}
+
+// Diff返回一个新数组，其中包含所有在a中存在、但不在other中存在的数据项
+// (类似PHP array_diff)。由于a、other均已经有序，这里通过一次归并扫描
+// 即可在O(n+m)内完成，而不需要O(n·m)的逐一比对；这要求other.array
+// 按照a.compareFunc同样的顺序有序排列——若other通过SetComparator使用了
+// 与a不同的比较规则，归并扫描的结果将是不正确的。
+func (a *SortedStringArray) Diff(other *SortedStringArray) *SortedStringArray {
+    a.mu.RLock()
+    defer a.mu.RUnlock()
+    if a != other {
+        other.mu.RLock()
+        defer other.mu.RUnlock()
+    }
+    result := make([]string, 0)
+    j := 0
+    for i := 0; i < len(a.array); i++ {
+        for j < len(other.array) && a.compareFunc(other.array[j], a.array[i]) < 0 {
+            j++
+        }
+        if j < len(other.array) && a.compareFunc(other.array[j], a.array[i]) == 0 {
+            continue
+        }
+        result = append(result, a.array[i])
+    }
+    return NewSortedStringArrayFrom(result, !a.mu.IsSafe())
+}
+
+// Intersect返回一个新数组，其中包含所有同时在a和other中存在的数据项
+// (类似PHP array_intersect)。与Diff一样，通过一次归并扫描在O(n+m)
+// 内完成，同样要求other.array按照a.compareFunc的顺序有序排列。
+func (a *SortedStringArray) Intersect(other *SortedStringArray) *SortedStringArray {
+    a.mu.RLock()
+    defer a.mu.RUnlock()
+    if a != other {
+        other.mu.RLock()
+        defer other.mu.RUnlock()
+    }
+    result := make([]string, 0)
+    j := 0
+    for i := 0; i < len(a.array); i++ {
+        for j < len(other.array) && a.compareFunc(other.array[j], a.array[i]) < 0 {
+            j++
+        }
+        if j < len(other.array) && a.compareFunc(other.array[j], a.array[i]) == 0 {
+            result = append(result, a.array[i])
+        }
+    }
+    return NewSortedStringArrayFrom(result, !a.mu.IsSafe())
+}
+
+// MarshalJSON实现了json.Marshaler接口，序列化结果为底层有序数组。
+func (a *SortedStringArray) MarshalJSON() ([]byte, error) {
+    return json.Marshal(a.Slice())
+}
+
+// UnmarshalJSON实现了json.Unmarshaler接口，解码后的数据会按照当前
+// compareFunc重新排序，若unique为true还会去除重复数据项。
+func (a *SortedStringArray) UnmarshalJSON(b []byte) error {
+    if a.mu == nil {
+        *a = *NewSortedStringArraySize(0)
+    }
+    var array []string
+    if err := json.Unmarshal(b, &array); err != nil {
+        return err
+    }
+    a.mu.Lock()
+    a.array = array
+    a.sortArray()
+    a.mu.Unlock()
+    if a.unique.Val() {
+        a.Unique()
+    }
+    a.indexDirty.Set(true)
+    return nil
+}
+
+// GobEncode实现了gob.GobEncoder接口，编码结果为底层有序数组。
+func (a *SortedStringArray) GobEncode() ([]byte, error) {
+    buffer := bytes.Buffer{}
+    if err := gob.NewEncoder(&buffer).Encode(a.Slice()); err != nil {
+        return nil, err
+    }
+    return buffer.Bytes(), nil
+}
+
+// GobDecode实现了gob.GobDecoder接口，解码后的数据会按照当前compareFunc
+// 重新排序，若unique为true还会去除重复数据项。
+func (a *SortedStringArray) GobDecode(buf []byte) error {
+    if a.mu == nil {
+        *a = *NewSortedStringArraySize(0)
+    }
+    var array []string
+    if err := gob.NewDecoder(bytes.NewReader(buf)).Decode(&array); err != nil {
+        return err
+    }
+    a.mu.Lock()
+    a.array = array
+    a.sortArray()
+    a.mu.Unlock()
+    if a.unique.Val() {
+        a.Unique()
+    }
+    a.indexDirty.Set(true)
+    return nil
+}
+
+// Iterator返回一个只读channel，由一个新的goroutine按顺序将数组中的各个
+// 数据项发送到该channel，调用方可以通过for range流式读取数据而不需要
+// 先复制整个底层slice。每次只在读取单个元素时持有读锁，发送到channel的
+// 过程不持有锁，因此调用方提前退出for range(未读完channel)也不会让
+// 读锁被永久占用。channel在遍历完成后会被关闭。
+func (a *SortedStringArray) Iterator() <-chan string {
+    ch := make(chan string)
+    go func() {
+        defer close(ch)
+        for i := 0; ; i++ {
+            a.mu.RLock()
+            if i >= len(a.array) {
+                a.mu.RUnlock()
+                return
+            }
+            v := a.array[i]
+            a.mu.RUnlock()
+            ch <- v
+        }
+    }()
+    return ch
+}
+
+// IteratorAsc在读锁保护下按索引从小到大遍历数组，对每一个数据项调用f，
+// 若f返回false则提前结束遍历。
+func (a *SortedStringArray) IteratorAsc(f func(k int, v string) bool) {
+    a.mu.RLock()
+    defer a.mu.RUnlock()
+    for k, v := range a.array {
+        if !f(k, v) {
+            break
+        }
+    }
 }
\ No newline at end of file
@@ -0,0 +1,455 @@
+// Copyright 2018 gf Author(https://gitee.com/johng/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://gitee.com/johng/gf.
+
+package garray
+
+import (
+    "bytes"
+    "encoding/gob"
+    "encoding/json"
+    "gitee.com/johng/gf/g/container/garray/iterator"
+    "sort"
+    "strings"
+    "testing"
+)
+
+// bruteSearchSubstring扫描elems，返回所有包含substr的元素索引，作为
+// SearchSubstring的参照实现。
+func bruteSearchSubstring(elems []string, substr string) []int {
+    var result []int
+    for i, v := range elems {
+        if strings.Contains(v, substr) {
+            result = append(result, i)
+        }
+    }
+    return result
+}
+
+// bruteSearchPrefix扫描elems，返回所有以prefix为前缀的元素索引，作为
+// SearchPrefix的参照实现。
+func bruteSearchPrefix(elems []string, prefix string) []int {
+    var result []int
+    for i, v := range elems {
+        if strings.HasPrefix(v, prefix) {
+            result = append(result, i)
+        }
+    }
+    return result
+}
+
+func TestSortedStringArraySearchSubstring(t *testing.T) {
+    // 故意使用存在重叠前缀/重复字符的元素，这类情况最容易暴露后缀数组
+    // 构建过程中排名更新不一致的问题。
+    elems := []string{"aa", "aaa", "aaaa", "aaaaa", "banana", "ananas"}
+    a := NewSortedStringArrayFrom(append([]string{}, elems...))
+    a.EnableIndex(true)
+
+    cases := []string{"aaa", "aa", "a", "ana", "na", "zzz"}
+    for _, substr := range cases {
+        got := a.SearchSubstring(substr)
+        want := bruteSearchSubstring(a.Slice(), substr)
+        sort.Ints(got)
+        sort.Ints(want)
+        if !intSlicesEqual(got, want) {
+            t.Fatalf("SearchSubstring(%q) = %v, want %v", substr, got, want)
+        }
+    }
+}
+
+func TestSortedStringArraySearchPrefix(t *testing.T) {
+    elems := []string{"aa", "aaa", "aaaa", "aaaaa", "banana", "ananas"}
+    a := NewSortedStringArrayFrom(append([]string{}, elems...))
+    a.EnableIndex(true)
+
+    cases := []string{"aa", "aaa", "ban", "ana", "zzz"}
+    for _, prefix := range cases {
+        got := a.SearchPrefix(prefix)
+        want := bruteSearchPrefix(a.Slice(), prefix)
+        sort.Ints(got)
+        sort.Ints(want)
+        if !intSlicesEqual(got, want) {
+            t.Fatalf("SearchPrefix(%q) = %v, want %v", prefix, got, want)
+        }
+    }
+}
+
+// TestSortedStringArraySearchSubstringRepetitive覆盖review中指出的回归
+// 场景：全部由重复字符组成的语料，后缀数组中存在大量相同前缀的分组。
+func TestSortedStringArraySearchSubstringRepetitive(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"aaaaaaaa", "mississippi", strings.Repeat("ab", 50)})
+    a.EnableIndex(true)
+
+    cases := []string{"aaaa", "ssiss", "ababab", "ppi", "zzz"}
+    for _, substr := range cases {
+        got := a.SearchSubstring(substr)
+        want := bruteSearchSubstring(a.Slice(), substr)
+        sort.Ints(got)
+        sort.Ints(want)
+        if !intSlicesEqual(got, want) {
+            t.Fatalf("SearchSubstring(%q) = %v, want %v", substr, got, want)
+        }
+    }
+}
+
+func intSlicesEqual(a, b []int) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return true
+}
+
+func strSlicesEqual(a, b []string) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return true
+}
+
+// TestSortedStringArrayBeginEnd覆盖Begin/End正向迭代器，确认其按照升序
+// 依次遍历到数组末尾(End代表的"末尾之后"位置)为止。
+func TestSortedStringArrayBeginEnd(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"c", "a", "b"})
+    var got []string
+    for it := a.Begin(); it.IsValid(); it.Next() {
+        got = append(got, it.Value())
+    }
+    if want := []string{"a", "b", "c"}; !strSlicesEqual(got, want) {
+        t.Fatalf("Begin/End iteration = %v, want %v", got, want)
+    }
+    if a.End().IsValid() {
+        t.Fatalf("End() iterator should not be valid")
+    }
+}
+
+// TestSortedStringArrayRBeginREnd覆盖RBegin/REnd反向迭代器，确认其按照
+// 降序依次遍历到REnd代表的"首个元素之前"位置为止。
+func TestSortedStringArrayRBeginREnd(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"c", "a", "b"})
+    var got []string
+    for it := a.RBegin(); it.IsValid(); it.Next() {
+        got = append(got, it.Value())
+    }
+    if want := []string{"c", "b", "a"}; !strSlicesEqual(got, want) {
+        t.Fatalf("RBegin/REnd iteration = %v, want %v", got, want)
+    }
+    if a.REnd().IsValid() {
+        t.Fatalf("REnd() iterator should not be valid")
+    }
+}
+
+// TestSortedStringArraySetComparator验证替换比较函数后数组会立即按照
+// 新的比较规则重新排序。
+func TestSortedStringArraySetComparator(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a", "c", "b"})
+    a.SetComparator(iterator.Reverse(func(v1, v2 string) int {
+        return strings.Compare(v1, v2)
+    }))
+    if want := []string{"c", "b", "a"}; !strSlicesEqual(a.Slice(), want) {
+        t.Fatalf("after SetComparator, Slice() = %v, want %v", a.Slice(), want)
+    }
+}
+
+// TestSortedStringArrayLowerBoundUpperBound验证iterator.LowerBound/
+// UpperBound作用在SortedStringArray的Begin()迭代器上的行为。
+func TestSortedStringArrayLowerBoundUpperBound(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a", "b", "b", "d"})
+    cmp := func(v1, v2 string) int { return strings.Compare(v1, v2) }
+
+    if !iterator.LowerBound(a.Begin(), "b", cmp) {
+        t.Fatalf("LowerBound(%q) should find a position", "b")
+    }
+    if it := a.Begin(); !iterator.LowerBound(it, "b", cmp) || it.Value() != "b" {
+        t.Fatalf("LowerBound(%q).Value() = %q, want %q", "b", it.Value(), "b")
+    }
+    if it := a.Begin(); !iterator.UpperBound(it, "b", cmp) || it.Value() != "d" {
+        t.Fatalf("UpperBound(%q).Value() = %q, want %q", "b", it.Value(), "d")
+    }
+    if it := a.Begin(); iterator.UpperBound(it, "d", cmp) {
+        t.Fatalf("UpperBound(%q) should not find any position beyond the last element", "d")
+    }
+}
+
+// TestSortedStringArrayForEachCountIf验证iterator.ForEach/CountIf作用在
+// SortedStringArray的Begin()迭代器上的行为。
+func TestSortedStringArrayForEachCountIf(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a", "bb", "ccc", "dddd"})
+    var got []string
+    iterator.ForEach(a.Begin(), func(value string) {
+        got = append(got, value)
+    })
+    if want := []string{"a", "bb", "ccc", "dddd"}; !strSlicesEqual(got, want) {
+        t.Fatalf("ForEach collected %v, want %v", got, want)
+    }
+    count := iterator.CountIf(a.Begin(), func(value string) bool {
+        return len(value) >= 2
+    })
+    if count != 3 {
+        t.Fatalf("CountIf(len>=2) = %d, want 3", count)
+    }
+}
+
+// TestSortedStringArrayRemoveIf验证RemoveIf通过iterator.RemoveIf完成
+// 紧凑移动后，数组正确截断并保持有序。
+func TestSortedStringArrayRemoveIf(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a", "bb", "ccc", "dddd", "e"})
+    removed := a.RemoveIf(func(value string) bool {
+        return len(value) >= 2
+    })
+    if removed != 3 {
+        t.Fatalf("RemoveIf removed = %d, want 3", removed)
+    }
+    if want := []string{"a", "e"}; !strSlicesEqual(a.Slice(), want) {
+        t.Fatalf("after RemoveIf, Slice() = %v, want %v", a.Slice(), want)
+    }
+
+    // 没有任何元素满足pred时，数组保持不变。
+    a2 := NewSortedStringArrayFrom([]string{"a", "b"})
+    if removed := a2.RemoveIf(func(value string) bool { return false }); removed != 0 {
+        t.Fatalf("RemoveIf with no match removed = %d, want 0", removed)
+    }
+    if want := []string{"a", "b"}; !strSlicesEqual(a2.Slice(), want) {
+        t.Fatalf("after no-op RemoveIf, Slice() = %v, want %v", a2.Slice(), want)
+    }
+}
+
+// TestSortedStringArrayDiffIntersect覆盖Diff/Intersect在存在重复元素时
+// 的归并扫描边界情况：重复值在两个数组中只需满足"存在"即可，不做多重
+// 集意义上的数量抵消。
+func TestSortedStringArrayDiffIntersect(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a", "a", "b", "c", "c", "d"})
+    b := NewSortedStringArrayFrom([]string{"a", "c", "c", "c", "e"})
+
+    diff := a.Diff(b)
+    if want := []string{"b", "d"}; !strSlicesEqual(diff.Slice(), want) {
+        t.Fatalf("Diff() = %v, want %v", diff.Slice(), want)
+    }
+
+    inter := a.Intersect(b)
+    if want := []string{"a", "a", "c", "c"}; !strSlicesEqual(inter.Slice(), want) {
+        t.Fatalf("Intersect() = %v, want %v", inter.Slice(), want)
+    }
+
+    // 自己与自己做Diff/Intersect，不应死锁(a != other分支被跳过)。
+    if got := a.Diff(a); got.Len() != 0 {
+        t.Fatalf("a.Diff(a) = %v, want empty", got.Slice())
+    }
+    if got := a.Intersect(a); !strSlicesEqual(got.Slice(), a.Slice()) {
+        t.Fatalf("a.Intersect(a) = %v, want %v", got.Slice(), a.Slice())
+    }
+}
+
+// TestSortedStringArrayCountValues验证CountValues正确统计每个数据项
+// 出现的次数。
+func TestSortedStringArrayCountValues(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a", "b", "a", "c", "a"})
+    got := a.CountValues()
+    want := map[string]int{"a": 3, "b": 1, "c": 1}
+    if len(got) != len(want) {
+        t.Fatalf("CountValues() = %v, want %v", got, want)
+    }
+    for k, v := range want {
+        if got[k] != v {
+            t.Fatalf("CountValues()[%q] = %d, want %d", k, got[k], v)
+        }
+    }
+}
+
+// TestSortedStringArrayFillPad验证Fill/Pad的基本行为，以及在SetUnique(true)
+// 时Add的去重规则会让实际新增的数据项少于请求的数目。
+func TestSortedStringArrayFillPad(t *testing.T) {
+    a := NewSortedStringArray()
+    if err := a.Fill(0, 3, "x"); err != nil {
+        t.Fatalf("Fill() error = %v", err)
+    }
+    if want := []string{"x", "x", "x"}; !strSlicesEqual(a.Slice(), want) {
+        t.Fatalf("after Fill, Slice() = %v, want %v", a.Slice(), want)
+    }
+    if err := a.Fill(100, 1, "y"); err == nil {
+        t.Fatalf("Fill() with out-of-bounds startIndex should return an error")
+    }
+
+    b := NewSortedStringArray()
+    b.Pad(3, "y")
+    if want := []string{"y", "y", "y"}; !strSlicesEqual(b.Slice(), want) {
+        t.Fatalf("after Pad, Slice() = %v, want %v", b.Slice(), want)
+    }
+    lenBefore := b.Len()
+    b.Pad(2, "y")
+    if b.Len() != lenBefore {
+        t.Fatalf("Pad() with size <= current length should be a no-op, Len() = %d, want %d", b.Len(), lenBefore)
+    }
+
+    // unique为true时，Fill/Pad内部的Add会静默跳过已存在的value，
+    // 因此实际新增的数目会少于请求的数目。
+    u := NewSortedStringArrayFrom([]string{"z"})
+    u.SetUnique(true)
+    if err := u.Fill(0, 5, "z"); err != nil {
+        t.Fatalf("Fill() error = %v", err)
+    }
+    if want := []string{"z"}; !strSlicesEqual(u.Slice(), want) {
+        t.Fatalf("Fill() on a unique array with an existing value should add nothing, Slice() = %v, want %v", u.Slice(), want)
+    }
+    u.Pad(5, "z")
+    if want := []string{"z"}; !strSlicesEqual(u.Slice(), want) {
+        t.Fatalf("Pad() on a unique array with an existing value should add nothing, Slice() = %v, want %v", u.Slice(), want)
+    }
+}
+
+// TestSortedStringArrayWalk验证Walk在SetUnique(true)时，会在f可能引入
+// 重复数据项之后重新调用Unique()去重。
+func TestSortedStringArrayWalk(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a1", "a2", "b1"})
+    a.SetUnique(true)
+    a.Walk(func(value string) string {
+        return value[:1]
+    })
+    if want := []string{"a", "b"}; !strSlicesEqual(a.Slice(), want) {
+        t.Fatalf("after Walk on a unique array, Slice() = %v, want %v", a.Slice(), want)
+    }
+
+    // 非unique数组的Walk不应该去重。
+    b := NewSortedStringArrayFrom([]string{"a1", "a2", "b1"})
+    b.Walk(func(value string) string {
+        return value[:1]
+    })
+    if want := []string{"a", "a", "b"}; !strSlicesEqual(b.Slice(), want) {
+        t.Fatalf("after Walk on a non-unique array, Slice() = %v, want %v", b.Slice(), want)
+    }
+}
+
+// TestSortedStringArrayReverse验证Reverse返回数组的逆序拷贝，且不影响
+// 原数组的有序性。
+func TestSortedStringArrayReverse(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"c", "a", "b"})
+    got := a.Reverse()
+    if want := []string{"c", "b", "a"}; !strSlicesEqual(got, want) {
+        t.Fatalf("Reverse() = %v, want %v", got, want)
+    }
+    if want := []string{"a", "b", "c"}; !strSlicesEqual(a.Slice(), want) {
+        t.Fatalf("Reverse() should not mutate the original array, Slice() = %v, want %v", a.Slice(), want)
+    }
+}
+
+// TestSortedStringArrayIsEmpty验证IsEmpty在数组为空/非空时的返回值。
+func TestSortedStringArrayIsEmpty(t *testing.T) {
+    a := NewSortedStringArray()
+    if !a.IsEmpty() {
+        t.Fatalf("IsEmpty() on a new array = false, want true")
+    }
+    a.Add("x")
+    if a.IsEmpty() {
+        t.Fatalf("IsEmpty() after Add = true, want false")
+    }
+}
+
+// TestSortedStringArrayRemoveValue验证RemoveValue在数据项存在/不存在
+// 时的返回值及对数组的影响。
+func TestSortedStringArrayRemoveValue(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"a", "b", "c"})
+    if !a.RemoveValue("b") {
+        t.Fatalf("RemoveValue(%q) = false, want true", "b")
+    }
+    if want := []string{"a", "c"}; !strSlicesEqual(a.Slice(), want) {
+        t.Fatalf("after RemoveValue, Slice() = %v, want %v", a.Slice(), want)
+    }
+    if a.RemoveValue("z") {
+        t.Fatalf("RemoveValue(%q) = true, want false", "z")
+    }
+}
+
+// TestSortedStringArrayJSONRoundTrip验证MarshalJSON/UnmarshalJSON的
+// 往返结果会按照compareFunc重新排序，并在unique为true时去重。
+func TestSortedStringArrayJSONRoundTrip(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"c", "a", "b"})
+    b, err := json.Marshal(a)
+    if err != nil {
+        t.Fatalf("json.Marshal() error = %v", err)
+    }
+
+    decoded := NewSortedStringArrayFrom([]string{"placeholder"})
+    decoded.SetUnique(true)
+    if err := json.Unmarshal([]byte(`["c", "a", "b", "a"]`), decoded); err != nil {
+        t.Fatalf("json.Unmarshal() error = %v", err)
+    }
+    if want := []string{"a", "b", "c"}; !strSlicesEqual(decoded.Slice(), want) {
+        t.Fatalf("after UnmarshalJSON with unique, Slice() = %v, want %v", decoded.Slice(), want)
+    }
+
+    plain := NewSortedStringArray()
+    if err := json.Unmarshal(b, plain); err != nil {
+        t.Fatalf("json.Unmarshal() error = %v", err)
+    }
+    if want := []string{"a", "b", "c"}; !strSlicesEqual(plain.Slice(), want) {
+        t.Fatalf("round-tripped Slice() = %v, want %v", plain.Slice(), want)
+    }
+}
+
+// TestSortedStringArrayGobRoundTrip验证GobEncode/GobDecode的往返结果
+// 会按照compareFunc重新排序，并在unique为true时去重。
+func TestSortedStringArrayGobRoundTrip(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"c", "a", "b"})
+    encoded, err := a.GobEncode()
+    if err != nil {
+        t.Fatalf("GobEncode() error = %v", err)
+    }
+    plain := NewSortedStringArray()
+    if err := plain.GobDecode(encoded); err != nil {
+        t.Fatalf("GobDecode() error = %v", err)
+    }
+    if want := []string{"a", "b", "c"}; !strSlicesEqual(plain.Slice(), want) {
+        t.Fatalf("round-tripped Slice() = %v, want %v", plain.Slice(), want)
+    }
+
+    var buf bytes.Buffer
+    if err := gob.NewEncoder(&buf).Encode([]string{"c", "a", "b", "a"}); err != nil {
+        t.Fatalf("gob.Encode() error = %v", err)
+    }
+    decoded := NewSortedStringArrayFrom([]string{"placeholder"})
+    decoded.SetUnique(true)
+    if err := decoded.GobDecode(buf.Bytes()); err != nil {
+        t.Fatalf("GobDecode() error = %v", err)
+    }
+    if want := []string{"a", "b", "c"}; !strSlicesEqual(decoded.Slice(), want) {
+        t.Fatalf("after GobDecode with unique, Slice() = %v, want %v", decoded.Slice(), want)
+    }
+}
+
+// TestSortedStringArrayIterator验证Iterator()返回的channel按升序产出
+// 数组中的所有数据项。
+func TestSortedStringArrayIterator(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"c", "a", "b"})
+    var got []string
+    for v := range a.Iterator() {
+        got = append(got, v)
+    }
+    if want := []string{"a", "b", "c"}; !strSlicesEqual(got, want) {
+        t.Fatalf("Iterator() produced %v, want %v", got, want)
+    }
+}
+
+// TestSortedStringArrayIteratorAsc验证IteratorAsc按索引从小到大遍历，
+// 并且f返回false时能够提前终止遍历。
+func TestSortedStringArrayIteratorAsc(t *testing.T) {
+    a := NewSortedStringArrayFrom([]string{"c", "a", "b"})
+    var got []string
+    a.IteratorAsc(func(k int, v string) bool {
+        got = append(got, v)
+        return v != "b"
+    })
+    if want := []string{"a", "b"}; !strSlicesEqual(got, want) {
+        t.Fatalf("IteratorAsc() produced %v, want %v (should stop after %q)", got, want, "b")
+    }
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 gf Author(https://gitee.com/johng/gf). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://gitee.com/johng/gf.
+
+// Package iterator提供了一套类似gostl的比较器/迭代器抽象，供garray下的
+// 有序数组类型使用，使调用方无需了解具体类型内部的compareFunc字段即可
+// 自定义比较规则，并对任意实现了BidirectionalIterator的类型使用统一的
+// STL风格算法(LowerBound/UpperBound/ForEach/CountIf/RemoveIf)。
+package iterator
+
+// Comparator用于比较两个string类型的值，返回值：-1: v1 < v2；0: v1 == v2；1: v1 > v2.
+type Comparator func(v1, v2 string) int
+
+// Reverse返回一个与cmp比较结果相反的Comparator，便于构造降序等场景。
+func Reverse(cmp Comparator) Comparator {
+    return func(v1, v2 string) int {
+        return -cmp(v1, v2)
+    }
+}
+
+// Chain依次按顺序使用cmps中的比较器进行比较，前一个比较器的结果为0时
+// 才会使用下一个，用于需要多级比较规则的场景。
+func Chain(cmps ...Comparator) Comparator {
+    return func(v1, v2 string) int {
+        for _, cmp := range cmps {
+            if r := cmp(v1, v2); r != 0 {
+                return r
+            }
+        }
+        return 0
+    }
+}
+
+// BidirectionalIterator定义了一个可双向遍历的迭代器，garray下的有序
+// 数组类型通过Begin()/End()/RBegin()/REnd()返回该接口的实现。
+type BidirectionalIterator interface {
+    // Next将迭代器向前移动一个位置，返回移动后迭代器是否仍然有效。
+    Next() bool
+    // Prev将迭代器向后移动一个位置，返回移动后迭代器是否仍然有效。
+    Prev() bool
+    // Value返回迭代器当前指向的值。
+    Value() string
+    // SetValue修改迭代器当前指向的值。
+    SetValue(value string)
+    // IsValid返回迭代器当前位置是否有效(未越界)。
+    IsValid() bool
+}
+
+// LowerBound从it当前位置开始向前查找第一个不小于value的位置(即cmp(当前值,
+// value) >= 0)，找到则将it移动到该位置并返回true，否则it将移动至末尾
+// (IsValid()为false)并返回false。调用方如需保留原始位置，应传入一个
+// 新获取的迭代器(例如array.Begin())。
+func LowerBound(it BidirectionalIterator, value string, cmp Comparator) bool {
+    for it.IsValid() {
+        if cmp(it.Value(), value) >= 0 {
+            return true
+        }
+        if !it.Next() {
+            break
+        }
+    }
+    return false
+}
+
+// UpperBound从it当前位置开始向前查找第一个大于value的位置(即cmp(当前值,
+// value) > 0)，找到则将it移动到该位置并返回true，否则返回false。
+// 调用方如需保留原始位置，应传入一个新获取的迭代器。
+func UpperBound(it BidirectionalIterator, value string, cmp Comparator) bool {
+    for it.IsValid() {
+        if cmp(it.Value(), value) > 0 {
+            return true
+        }
+        if !it.Next() {
+            break
+        }
+    }
+    return false
+}
+
+// ForEach从it当前位置开始依次向前遍历，对每一个值调用f，直至迭代器失效。
+func ForEach(it BidirectionalIterator, f func(value string)) {
+    for it.IsValid() {
+        f(it.Value())
+        if !it.Next() {
+            break
+        }
+    }
+}
+
+// CountIf从it当前位置开始依次向前遍历，统计满足pred的元素个数。
+func CountIf(it BidirectionalIterator, pred func(value string) bool) int {
+    count := 0
+    for it.IsValid() {
+        if pred(it.Value()) {
+            count++
+        }
+        if !it.Next() {
+            break
+        }
+    }
+    return count
+}
+
+// RemoveIf从it当前位置开始依次向前遍历，将所有不满足pred的元素紧凑地
+// 前移，占据之前满足pred的元素腾出的位置(类似C++的std::remove_if)，
+// 返回满足pred(即被视为"删除"的)元素个数。RemoveIf本身并不收缩底层
+// 容器——它只通过it的Next/Prev/Value/SetValue/IsValid完成紧凑移动，
+// 调用方需要根据返回的计数自行截断容器末尾多余的元素。
+func RemoveIf(it BidirectionalIterator, pred func(value string) bool) int {
+    removed := 0
+    for it.IsValid() {
+        value := it.Value()
+        if pred(value) {
+            removed++
+        } else if removed > 0 {
+            for i := 0; i < removed; i++ {
+                it.Prev()
+            }
+            it.SetValue(value)
+            for i := 0; i < removed; i++ {
+                it.Next()
+            }
+        }
+        if !it.Next() {
+            break
+        }
+    }
+    return removed
+}